@@ -0,0 +1,389 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CacheConfig controls how long cached space/box/card responses are
+// considered fresh before a command hits api.kinopio.club again.
+type CacheConfig struct {
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// defaultCacheTTL is used when the user hasn't set `cache.ttl` in
+// kinopio.yaml.
+const defaultCacheTTL = 15 * time.Minute
+
+// cacheEntry is what's persisted to disk for a cached space/box. ETag
+// and LastModified, when the API supplied them, let a stale entry be
+// revalidated with a conditional request instead of always refetching
+// the full body once the TTL has elapsed.
+type cacheEntry struct {
+	FetchedAt    time.Time       `json:"fetched_at"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"last_modified,omitempty"`
+	Data         json.RawMessage `json:"data"`
+}
+
+// extractCacheFlags removes --offline and --refresh from os.Args in
+// place, so the rest of Run can keep parsing commands by index, and
+// reports whether each flag was present.
+func extractCacheFlags() (offline, refresh bool) {
+	var kept []string
+	for _, a := range os.Args {
+		switch a {
+		case `--offline`:
+			offline = true
+		case `--refresh`:
+			refresh = true
+		default:
+			kept = append(kept, a)
+		}
+	}
+	os.Args = kept
+	return offline, refresh
+}
+
+// ttl returns the configured cache TTL, falling back to defaultCacheTTL.
+func (c *Config) ttl() time.Duration {
+	if c.Cache.TTL <= 0 {
+		return defaultCacheTTL
+	}
+	return c.Cache.TTL
+}
+
+// cacheDir returns the directory cached responses are written under.
+func cacheDir(conf *Config) string {
+	return filepath.Join(conf.DirPath, `cache`)
+}
+
+// cachePath returns the file a given kind/id pair is cached at, e.g.
+// cache/spaces/<id>.json.
+func cachePath(conf *Config, kind, id string) string {
+	return filepath.Join(cacheDir(conf), kind, id+`.json`)
+}
+
+// readCache loads the cache entry for kind/id, if one is on disk.
+// fresh reports whether it's still within the configured TTL (offline
+// always counts as fresh, since there's nowhere else to look); found
+// reports whether an entry exists at all, stale or not, so a stale
+// entry's ETag/LastModified can still be sent as a validator.
+func readCache(conf *Config, kind, id string, offline bool) (entry cacheEntry, fresh, found bool) {
+	data, err := os.ReadFile(cachePath(conf, kind, id))
+	if err != nil {
+		return cacheEntry{}, false, false
+	}
+
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return cacheEntry{}, false, false
+	}
+
+	if offline || time.Since(entry.FetchedAt) <= conf.ttl() {
+		return entry, true, true
+	}
+	return entry, false, true
+}
+
+// writeCache persists v for kind/id along with the validators the API
+// returned, overwriting any existing entry.
+func writeCache(conf *Config, kind, id, etag, lastModified string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("error marshalling cache entry: %v", err)
+	}
+
+	entry := cacheEntry{FetchedAt: time.Now(), ETag: etag, LastModified: lastModified, Data: data}
+	return putCacheEntry(conf, kind, id, entry)
+}
+
+// touchCache rewrites entry with a fresh FetchedAt, keeping its Data
+// and validators as-is. Used after a 304 Not Modified response to
+// extend a stale entry's TTL without refetching its body.
+func touchCache(conf *Config, kind, id string, entry cacheEntry) error {
+	entry.FetchedAt = time.Now()
+	return putCacheEntry(conf, kind, id, entry)
+}
+
+// putCacheEntry writes entry to kind/id's cache file, creating parent
+// directories as needed.
+func putCacheEntry(conf *Config, kind, id string, entry cacheEntry) error {
+	out, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error marshalling cache entry: %v", err)
+	}
+
+	path := cachePath(conf, kind, id)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("error creating cache directory: %v", err)
+	}
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		return fmt.Errorf("error writing cache entry: %v", err)
+	}
+
+	return nil
+}
+
+// conditionalGet issues a GET to url, sending If-None-Match /
+// If-Modified-Since from prior's validators when present, and reports
+// whether the server replied 304 Not Modified (in which case body is
+// nil and the caller should keep using its cached data).
+func conditionalGet(client *Client, url, key string, prior cacheEntry) (body []byte, etag, lastModified string, notModified bool, err error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, "", "", false, fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", key)
+	if prior.ETag != "" {
+		req.Header.Set("If-None-Match", prior.ETag)
+	}
+	if prior.LastModified != "" {
+		req.Header.Set("If-Modified-Since", prior.LastModified)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", "", false, err
+	}
+	defer resp.Body.Close()
+
+	etag = resp.Header.Get("ETag")
+	lastModified = resp.Header.Get("Last-Modified")
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, etag, lastModified, true, nil
+	}
+
+	body, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", "", false, fmt.Errorf("failed to read response body: %v", err)
+	}
+	return body, etag, lastModified, false, nil
+}
+
+// GetSpacesCached is GetSpaces, served from the local cache unless
+// refresh is set. A stale entry is revalidated with a conditional
+// request (If-None-Match/If-Modified-Since) before falling back to a
+// full refetch; on 304 its TTL is simply extended. In offline mode, a
+// missing cache entry is an error rather than a fallback to the
+// network.
+func GetSpacesCached(client *Client, conf *Config, key string, offline, refresh bool) ([]Space, error) {
+	const kind, id = `spaces`, `all`
+
+	entry, fresh, found := readCache(conf, kind, id, offline)
+	if !refresh && fresh {
+		var spaces []Space
+		if err := json.Unmarshal(entry.Data, &spaces); err == nil {
+			return spaces, nil
+		}
+	}
+
+	if offline {
+		if found {
+			var spaces []Space
+			if err := json.Unmarshal(entry.Data, &spaces); err == nil {
+				return spaces, nil
+			}
+		}
+		return nil, fmt.Errorf("no cached spaces available offline")
+	}
+
+	url := fmt.Sprintf("%s/user/spaces", client.baseURL)
+	body, etag, lastModified, notModified, err := conditionalGet(client, url, key, entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve spaces: %v", err)
+	}
+	if notModified {
+		if err := touchCache(conf, kind, id, entry); err != nil {
+			return nil, fmt.Errorf("failed to refresh cache entry: %v", err)
+		}
+		var spaces []Space
+		if err := json.Unmarshal(entry.Data, &spaces); err != nil {
+			return nil, fmt.Errorf("failed to unmarshall JSON: %v", err)
+		}
+		return spaces, nil
+	}
+
+	var spaces []Space
+	if err := json.Unmarshal(body, &spaces); err != nil {
+		return nil, fmt.Errorf("failed to unmarshall JSON: %v", err)
+	}
+	if err := writeCache(conf, kind, id, etag, lastModified, spaces); err != nil {
+		return nil, fmt.Errorf("failed to cache spaces: %v", err)
+	}
+	return spaces, nil
+}
+
+// GetSpaceCached is GetSpace, served from the local cache unless
+// refresh is set, with the same conditional-revalidation behavior as
+// GetSpacesCached.
+func GetSpaceCached(client *Client, conf *Config, id, key string, offline, refresh bool) (Space, error) {
+	const kind = `spaces`
+
+	entry, fresh, found := readCache(conf, kind, id, offline)
+	if !refresh && fresh {
+		var space Space
+		if err := json.Unmarshal(entry.Data, &space); err == nil {
+			return space, nil
+		}
+	}
+
+	if offline {
+		if found {
+			var space Space
+			if err := json.Unmarshal(entry.Data, &space); err == nil {
+				return space, nil
+			}
+		}
+		return Space{}, fmt.Errorf("no cached space %q available offline", id)
+	}
+
+	url := fmt.Sprintf("%s/space/%s", client.baseURL, id)
+	body, etag, lastModified, notModified, err := conditionalGet(client, url, key, entry)
+	if err != nil {
+		return Space{}, fmt.Errorf("failed to retrieve space: %v", err)
+	}
+	if notModified {
+		if err := touchCache(conf, kind, id, entry); err != nil {
+			return Space{}, fmt.Errorf("failed to refresh cache entry: %v", err)
+		}
+		var space Space
+		if err := json.Unmarshal(entry.Data, &space); err != nil {
+			return Space{}, fmt.Errorf("failed to unmarshall JSON: %v", err)
+		}
+		return space, nil
+	}
+
+	var space Space
+	if err := json.Unmarshal(body, &space); err != nil {
+		return Space{}, fmt.Errorf("failed to unmarshall JSON: %v", err)
+	}
+	if err := writeCache(conf, kind, id, etag, lastModified, space); err != nil {
+		return Space{}, fmt.Errorf("failed to cache space: %v", err)
+	}
+	return space, nil
+}
+
+// getBoxCached is getBox, served from the local cache unless refresh
+// is set, with the same conditional-revalidation behavior as
+// GetSpacesCached.
+func getBoxCached(client *Client, conf *Config, id, key string, offline, refresh bool) (Box, error) {
+	const kind = `boxes`
+
+	entry, fresh, found := readCache(conf, kind, id, offline)
+	if !refresh && fresh {
+		var box Box
+		if err := json.Unmarshal(entry.Data, &box); err == nil {
+			return box, nil
+		}
+	}
+
+	if offline {
+		if found {
+			var box Box
+			if err := json.Unmarshal(entry.Data, &box); err == nil {
+				return box, nil
+			}
+		}
+		return Box{}, fmt.Errorf("no cached box %q available offline", id)
+	}
+
+	url := fmt.Sprintf("%s/box/%s", client.baseURL, id)
+	body, etag, lastModified, notModified, err := conditionalGet(client, url, key, entry)
+	if err != nil {
+		return Box{}, fmt.Errorf("failed to retrieve box: %v", err)
+	}
+	if notModified {
+		if err := touchCache(conf, kind, id, entry); err != nil {
+			return Box{}, fmt.Errorf("failed to refresh cache entry: %v", err)
+		}
+		var box Box
+		if err := json.Unmarshal(entry.Data, &box); err != nil {
+			return Box{}, fmt.Errorf("failed to unmarshall JSON: %v", err)
+		}
+		return box, nil
+	}
+
+	var box Box
+	if err := json.Unmarshal(body, &box); err != nil {
+		return Box{}, fmt.Errorf("failed to unmarshall JSON: %v", err)
+	}
+	if err := writeCache(conf, kind, id, etag, lastModified, box); err != nil {
+		return Box{}, fmt.Errorf("failed to cache box: %v", err)
+	}
+	return box, nil
+}
+
+// CardsInBoxCached is CardsInBox, with the space and box lookups it
+// performs each served from the local cache unless refresh is set.
+func CardsInBoxCached(client *Client, conf *Config, spaceID, boxID, key string, offline, refresh bool) ([]Card, error) {
+	space, err := GetSpaceCached(client, conf, spaceID, key, offline, refresh)
+	if err != nil {
+		return []Card{}, fmt.Errorf("failed to retrieve space %q: %v", spaceID, err)
+	}
+	box, err := getBoxCached(client, conf, boxID, key, offline, refresh)
+	if err != nil {
+		return []Card{}, fmt.Errorf("failed to retrieve box %q: %v", boxID, err)
+	}
+
+	var cards []Card
+	for _, c := range space.Cards {
+		if isCardInBox(c, box) {
+			cards = append(cards, c)
+		}
+	}
+	return cards, nil
+}
+
+// clearCache removes every cached response under conf.DirPath.
+func clearCache(conf *Config) error {
+	if err := os.RemoveAll(cacheDir(conf)); err != nil {
+		return fmt.Errorf("failed to remove cache directory: %v", err)
+	}
+	return nil
+}
+
+// cacheStatus reports one line per cached entry found under
+// conf.DirPath, noting how long ago it was fetched.
+func cacheStatus(conf *Config) ([]string, error) {
+	var lines []string
+
+	err := filepath.Walk(cacheDir(conf), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var entry cacheEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(cacheDir(conf), path)
+		if err != nil {
+			rel = path
+		}
+		lines = append(lines, fmt.Sprintf("%s\tfetched %s ago", rel, time.Since(entry.FetchedAt).Round(time.Second)))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk cache directory: %v", err)
+	}
+
+	return lines, nil
+}
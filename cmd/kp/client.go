@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Sentinel errors returned by Client.Do once a response's status code
+// has been classified. Callers can compare against these with
+// errors.Is.
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrNotFound     = errors.New("not found")
+	ErrRateLimited  = errors.New("rate limited")
+)
+
+// HTTPConfig tunes the Client built from it: request timeout, retry
+// budget, and the token-bucket rate limiter shared by every request.
+type HTTPConfig struct {
+	TimeoutSeconds  int     `yaml:"timeout_seconds"`
+	MaxRetries      int     `yaml:"max_retries"`
+	RateLimitPerSec float64 `yaml:"rate_limit_per_second"`
+	Burst           int     `yaml:"burst"`
+}
+
+const (
+	defaultTimeout         = 10 * time.Second
+	defaultMaxRetries      = 3
+	defaultRateLimitPerSec = 5.0
+	defaultBurst           = 5
+)
+
+// apiError wraps one of the sentinel errors above with the status code
+// and response body that produced it.
+type apiError struct {
+	StatusCode int
+	Err        error
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%v (status %d): %s", e.Err, e.StatusCode, e.Body)
+}
+
+func (e *apiError) Unwrap() error { return e.Err }
+
+// Client wraps *http.Client with retries on 5xx/429 responses
+// (honoring Retry-After), a token-bucket rate limiter, and typed
+// errors chosen by the response's status code, carrying its body. AddCardToInbox, GetSpaces,
+// GetSpace, and getBox all route through it.
+type Client struct {
+	baseURL    string
+	http       *http.Client
+	limiter    *tokenBucket
+	maxRetries int
+}
+
+// NewClient builds a Client for baseURL, applying defaults for any
+// zero-valued field in conf.
+func NewClient(baseURL string, conf HTTPConfig) *Client {
+	timeout := time.Duration(conf.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	maxRetries := conf.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxRetries
+	}
+
+	rate := conf.RateLimitPerSec
+	if rate <= 0 {
+		rate = defaultRateLimitPerSec
+	}
+
+	burst := conf.Burst
+	if burst <= 0 {
+		burst = defaultBurst
+	}
+
+	return &Client{
+		baseURL:    baseURL,
+		http:       &http.Client{Timeout: timeout},
+		limiter:    newTokenBucket(rate, burst),
+		maxRetries: maxRetries,
+	}
+}
+
+// Do sends req, retrying on connection errors and 5xx/429 responses
+// with exponential backoff (honoring a Retry-After header when
+// present). The final response is classified into a typed error if its
+// status code indicates failure.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	var bodyCopy []byte
+	if req.Body != nil {
+		var err error
+		bodyCopy, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading request body: %v", err)
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(bodyCopy))
+	}
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		c.limiter.take()
+
+		if bodyCopy != nil {
+			req.Body = ioutil.NopCloser(bytes.NewReader(bodyCopy))
+		}
+
+		resp, err = c.http.Do(req)
+		if err != nil {
+			if attempt == c.maxRetries {
+				return nil, fmt.Errorf("error making request: %v", err)
+			}
+			time.Sleep(backoff(attempt))
+			continue
+		}
+
+		if !shouldRetry(resp.StatusCode) || attempt == c.maxRetries {
+			break
+		}
+
+		wait := backoff(attempt)
+		if ra := resp.Header.Get("Retry-After"); ra != "" {
+			if secs, err := strconv.Atoi(ra); err == nil {
+				wait = time.Duration(secs) * time.Second
+			}
+		}
+		resp.Body.Close()
+		time.Sleep(wait)
+	}
+
+	if err := classifyStatus(resp); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
+// shouldRetry reports whether a response with this status code is
+// worth retrying.
+func shouldRetry(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// backoff returns the exponential backoff delay for a given (zero
+// indexed) retry attempt: 500ms, 1s, 2s, 4s, ...
+func backoff(attempt int) time.Duration {
+	return 500 * time.Millisecond * time.Duration(1<<uint(attempt))
+}
+
+// classifyStatus turns a 401/403, 404 or 429 response into an apiError
+// wrapping the matching sentinel error, and any other non-2xx response
+// into a plain error, reading and closing the response body in the
+// process. 304 Not Modified is treated as success: it's the expected
+// outcome of a conditional request, not a failure.
+func classifyStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 || resp.StatusCode == http.StatusNotModified {
+		return nil
+	}
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	var sentinel error
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		sentinel = ErrUnauthorized
+	case http.StatusNotFound:
+		sentinel = ErrNotFound
+	case http.StatusTooManyRequests:
+		sentinel = ErrRateLimited
+	default:
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, body)
+	}
+	return &apiError{StatusCode: resp.StatusCode, Err: sentinel, Body: string(body)}
+}
+
+// tokenBucket is a minimal token-bucket rate limiter: tokens refill at
+// rate per second up to burst, and take() blocks until one is
+// available.
+type tokenBucket struct {
+	mu     sync.Mutex
+	tokens float64
+	max    float64
+	rate   float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		tokens: float64(burst),
+		max:    float64(burst),
+		rate:   rate,
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) take() {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.max {
+		b.tokens = b.max
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+		time.Sleep(wait)
+		b.mu.Lock()
+		b.tokens = 0
+		b.last = time.Now()
+	} else {
+		b.tokens--
+	}
+	b.mu.Unlock()
+}
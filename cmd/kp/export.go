@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const spaceExportUsage = `Export a space to Markdown or JSON
+
+USAGE
+  kp space export <id> [--format markdown|json] [-o <file>]
+
+FLAGS
+  --format <markdown|json>: Output format (default: markdown)
+  -o <file>:                Write to file instead of stdout`
+
+const spaceImportUsage = `Import a space from a Markdown or JSON export
+
+USAGE
+  kp space import <file> [--space <id>] [--dry-run]
+
+FLAGS
+  --space <id>: Space to import boxes and cards into. Required, and must
+                differ from a JSON export's embedded space ID — the API
+                has no way to create a space, so importing back into the
+                source space would duplicate every box and card in it.
+  --dry-run:    Print what would be created without calling the API`
+
+// ExportSpace fetches a space and writes it to w in the given format,
+// "markdown" (the default) or "json".
+func ExportSpace(client *Client, id, key, format string, w io.Writer) error {
+	space, err := GetSpace(client, id, key)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve space: %v", err)
+	}
+
+	switch format {
+	case "", `markdown`:
+		return writeMarkdownSpace(w, space)
+	case `json`:
+		return writeJSONSpace(w, space)
+	default:
+		return fmt.Errorf("unknown format %q, want markdown or json", format)
+	}
+}
+
+// writeMarkdownSpace renders a space as Markdown: boxes as level-2
+// headings with their contained cards (per isCardInBox) as a bullet
+// list, and any card that isn't in a box under an "Unboxed" heading.
+func writeMarkdownSpace(w io.Writer, space Space) error {
+	if _, err := fmt.Fprintf(w, "# %s\n\n", space.Name); err != nil {
+		return err
+	}
+
+	boxed := make(map[string]bool, len(space.Cards))
+	for _, box := range space.Boxes {
+		if _, err := fmt.Fprintf(w, "## %s\n\n", box.Name); err != nil {
+			return err
+		}
+		for _, c := range space.Cards {
+			if !isCardInBox(c, box) {
+				continue
+			}
+			boxed[c.ID] = true
+			if _, err := fmt.Fprintf(w, "- %s\n", c.Name); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+
+	var unboxed []Card
+	for _, c := range space.Cards {
+		if !boxed[c.ID] {
+			unboxed = append(unboxed, c)
+		}
+	}
+	if len(unboxed) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(w, "## Unboxed\n\n"); err != nil {
+		return err
+	}
+	for _, c := range unboxed {
+		if _, err := fmt.Fprintf(w, "- %s\n", c.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeJSONSpace writes space as indented JSON, a faithful round-trip
+// of everything GetSpace returned.
+func writeJSONSpace(w io.Writer, space Space) error {
+	data, err := json.MarshalIndent(space, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling space: %v", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// ImportSpace reads a Markdown or JSON export from path and re-creates
+// its boxes and cards via the API in spaceID, which the caller must
+// always pass explicitly: the API has no way to create a space, so
+// there's nowhere to land the restored boxes and cards but an existing
+// one, and for JSON exports that must not be the space the export was
+// taken from, or every box and card in it would be duplicated. dryRun
+// prints the plan instead of calling the API.
+func ImportSpace(client *Client, path, spaceID, key string, dryRun bool) error {
+	if spaceID == "" {
+		return fmt.Errorf("no space ID to import into, pass --space <id>")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %q: %v", path, err)
+	}
+
+	var space Space
+	if strings.HasSuffix(path, `.json`) {
+		if err := json.Unmarshal(data, &space); err != nil {
+			return fmt.Errorf("failed to parse JSON export: %v", err)
+		}
+		if spaceID == space.ID {
+			return fmt.Errorf("--space %s is the space this export was taken from; the API can't create a new space, so importing into it would duplicate every box and card, pass a different --space <id>", spaceID)
+		}
+	} else {
+		space = parseMarkdownSpace(data)
+	}
+
+	if dryRun {
+		fmt.Printf("would create %d box(es) and %d card(s) in space %s\n", len(space.Boxes), len(space.Cards), spaceID)
+		for _, b := range space.Boxes {
+			fmt.Printf("  box:  %s\n", b.Name)
+		}
+		for _, c := range space.Cards {
+			fmt.Printf("  card: %s\n", c.Name)
+		}
+		return nil
+	}
+
+	for _, box := range space.Boxes {
+		if _, err := CreateBox(client, spaceID, box, key); err != nil {
+			return fmt.Errorf("failed to create box %q: %v", box.Name, err)
+		}
+	}
+	for _, c := range space.Cards {
+		c.SpaceID = spaceID
+		if _, err := CreateCard(client, c, key); err != nil {
+			return fmt.Errorf("failed to create card %q: %v", c.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// parseMarkdownSpace parses the Markdown writeMarkdownSpace produces:
+// a level-1 heading for the space name, level-2 headings for box
+// names, and a bullet list of card names under each. Positions aren't
+// recoverable from Markdown, so imported cards land unboxed until
+// moved by hand.
+func parseMarkdownSpace(data []byte) Space {
+	var space Space
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case strings.HasPrefix(line, "# "):
+			space.Name = strings.TrimPrefix(line, "# ")
+		case strings.HasPrefix(line, "## "):
+			name := strings.TrimPrefix(line, "## ")
+			if name == `Unboxed` {
+				continue
+			}
+			space.Boxes = append(space.Boxes, Box{Name: name})
+		case strings.HasPrefix(line, "- "):
+			space.Cards = append(space.Cards, Card{Name: strings.TrimPrefix(line, "- ")})
+		}
+	}
+
+	return space
+}
+
+// parseExportImportFlags parses --format, -o, --space, and --dry-run
+// out of args, the tail of os.Args following `kp space export`/`import`.
+func parseExportImportFlags(args []string) (format, out, space string, dryRun bool, err error) {
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case `--format`:
+			i++
+			if i >= len(args) {
+				return "", "", "", false, fmt.Errorf("--format requires a value")
+			}
+			format = args[i]
+		case `-o`:
+			i++
+			if i >= len(args) {
+				return "", "", "", false, fmt.Errorf("-o requires a value")
+			}
+			out = args[i]
+		case `--space`:
+			i++
+			if i >= len(args) {
+				return "", "", "", false, fmt.Errorf("--space requires a value")
+			}
+			space = args[i]
+		case `--dry-run`:
+			dryRun = true
+		default:
+			return "", "", "", false, fmt.Errorf("unknown flag %q", args[i])
+		}
+	}
+	return format, out, space, dryRun, nil
+}
@@ -19,7 +19,7 @@ import (
 
 const (
 	lightGreyUnderlined = "\033[37;4m"
-	apiURL              = `https://api.kinopio.club`
+	defaultAPIURL       = `https://api.kinopio.club`
 	usage               = `Work with Kinopio from the command line.
 
 USAGE
@@ -30,7 +30,24 @@ CORE COMMANDS
   config:     Open config file using $EDITOR
   space:      Interact with user spaces
   roundrobin: Round robin kinopio cards
-  help:       Print this usage message`
+  tui:        Launch interactive terminal UI
+  cache:      Inspect or clear the local response cache
+  help:       Print this usage message
+
+FLAGS
+  --offline: Serve space/box/card data from the local cache only
+             (space ls, space view, roundrobin set; tui and space
+             export always call the API)
+  --refresh: Bypass the local cache and re-fetch from the API`
+
+	cacheUsage = `Inspect or clear the local response cache
+
+USAGE
+  kp cache <command>
+
+COMMANDS
+  clear:  Remove all cached spaces, boxes, and cards
+  status: List cached entries and their age`
 
 	inboxUsage = `Work with Kinopio inbox.
 
@@ -48,7 +65,9 @@ USAGE
 
 COMMANDS
   ls, list: Print all spaces
-  view:     View a space`
+  view:     View a space
+  export:   Export a space to Markdown or JSON
+  import:   Import a space from a Markdown or JSON export`
 
 	spaceViewUsage = `View a space
 
@@ -64,17 +83,21 @@ USAGE
   kp roundrobin [command] [args]
 
 COMMANDS
-  set <space_id> <box_id>: Specify the box whose cards you want to schedule
-  next:  Move to the next card in the scheduler
+  set <space_id> <box_id> [--time-slice <duration>]: Specify the box whose cards you want to schedule
+  next:                                              Move to the next card in the scheduler
+  run [--time-slice <duration>] [--rounds <n>] [--pause]: Run the scheduler in the foreground
   clear: Clear the round-robin state`
 )
 
 type Config struct {
-	DirPath      string    `yaml:"-"`
-	FilePath     string    `yaml:"-"`
-	APIKey       string    `yaml:"api_key"`
-	InboxSpaceID string    `yaml:"inbox_space_id"`
-	Schedule     Scheduler `yaml:"schedule"`
+	DirPath      string      `yaml:"-"`
+	FilePath     string      `yaml:"-"`
+	APIKey       string      `yaml:"api_key"`
+	APIURL       string      `yaml:"api_url"`
+	InboxSpaceID string      `yaml:"inbox_space_id"`
+	Schedule     Scheduler   `yaml:"schedule"`
+	Cache        CacheConfig `yaml:"cache"`
+	HTTP         HTTPConfig  `yaml:"http"`
 }
 
 type Space struct {
@@ -90,9 +113,9 @@ type Card struct {
 	Name    string `json:"name"`
 	Height  int    `json:"resizeHeight"`
 	Width   int    `json:"resizeWidth"`
-	X       int    `json: "x"`
-	Y       int    `json: "y"`
-	Z       int    `json: "z"`
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	Z       int    `json:"z"`
 }
 
 type Box struct {
@@ -100,8 +123,8 @@ type Box struct {
 	Name   string `json:"name"`
 	Height int    `json:"resizeHeight"`
 	Width  int    `json:"resizeWidth"`
-	X      int    `json: "x"`
-	Y      int    `json: "y"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
 }
 
 type Topic struct {
@@ -110,6 +133,18 @@ type Topic struct {
 	Duration time.Duration `json:"duration"`
 }
 
+// elapsed returns how much of the topic's time slice has been used up:
+// Duration accumulated from earlier runs, plus however long it's been
+// running since Start. A zero Start means the topic isn't currently
+// running (e.g. `kp roundrobin run --pause` froze it), so only the
+// accumulated Duration counts.
+func (t Topic) elapsed() time.Duration {
+	if t.Start.IsZero() {
+		return t.Duration
+	}
+	return t.Duration + time.Since(t.Start)
+}
+
 type Scheduler struct {
 	Topics    []Topic       `json:"topics"`
 	Current   int           `json:"current"`
@@ -117,6 +152,8 @@ type Scheduler struct {
 }
 
 func Run() error {
+	offline, refresh := extractCacheFlags()
+
 	args := os.Args
 	if len(args) == 1 {
 		fmt.Println(usage)
@@ -128,6 +165,8 @@ func Run() error {
 		return fmt.Errorf("failed to load config: %v", err)
 	}
 
+	client := NewClient(conf.APIURL, conf.HTTP)
+
 	switch strings.ToLower(os.Args[1]) {
 	case `inbox`, `i`:
 		if len(args) < 3 {
@@ -136,7 +175,7 @@ func Run() error {
 		}
 		switch strings.ToLower(os.Args[2]) {
 		case `view`: // TODO: View inbox
-			//spaceURL := fmt.Sprintf("%s/space/inbox", apiURL)
+			//spaceURL := fmt.Sprintf("%s/space/inbox", conf.APIURL)
 		case `add`, `a`:
 			if len(args) < 4 {
 				fmt.Println(inboxUsage)
@@ -147,7 +186,7 @@ func Run() error {
 				Name:    name,
 				SpaceID: conf.InboxSpaceID,
 			}
-			if err := AddCardToInbox(c, conf.APIKey); err != nil {
+			if err := AddCardToInbox(client, c, conf.APIKey); err != nil {
 				return fmt.Errorf("failed to add card to inbox: %v", err)
 			}
 		default:
@@ -160,7 +199,7 @@ func Run() error {
 		}
 		switch strings.ToLower(os.Args[2]) {
 		case `ls`, `list`: // List spaces owned by user
-			spaces, err := GetSpaces(conf.APIKey)
+			spaces, err := GetSpacesCached(client, conf, conf.APIKey, offline, refresh)
 			if err != nil {
 				return fmt.Errorf("failed to retrieve user spaces: %v", err)
 			}
@@ -184,7 +223,7 @@ func Run() error {
 
 			if len(args) == 4 {
 				id := os.Args[3]
-				space, err := GetSpace(id, conf.APIKey)
+				space, err := GetSpaceCached(client, conf, id, conf.APIKey, offline, refresh)
 				if err != nil {
 					return fmt.Errorf("failed to retrieve user space: %v", err)
 				}
@@ -225,7 +264,7 @@ func Run() error {
 				}
 				spaceID := os.Args[3]
 				boxID := os.Args[5]
-				cards, err := CardsInBox(spaceID, boxID, conf.APIKey)
+				cards, err := CardsInBoxCached(client, conf, spaceID, boxID, conf.APIKey, offline, refresh)
 				if err != nil {
 					return fmt.Errorf("failed to retrieve box: %v", err)
 				}
@@ -235,6 +274,44 @@ func Run() error {
 			default:
 				return fmt.Errorf("unknown command %q for \"kp space view <ID>\"\n\n%s", os.Args[5], spaceViewUsage)
 			}
+		case `export`:
+			if len(args) < 4 {
+				fmt.Println(spaceExportUsage)
+				return nil
+			}
+			id := os.Args[3]
+			format, out, _, _, err := parseExportImportFlags(os.Args[4:])
+			if err != nil {
+				return fmt.Errorf("invalid flags for \"kp space export\": %v", err)
+			}
+
+			w := os.Stdout
+			if out != "" {
+				f, err := os.Create(out)
+				if err != nil {
+					return fmt.Errorf("failed to create %q: %v", out, err)
+				}
+				defer f.Close()
+				w = f
+			}
+
+			if err := ExportSpace(client, id, conf.APIKey, format, w); err != nil {
+				return fmt.Errorf("failed to export space: %v", err)
+			}
+		case `import`:
+			if len(args) < 4 {
+				fmt.Println(spaceImportUsage)
+				return nil
+			}
+			path := os.Args[3]
+			_, _, spaceID, dryRun, err := parseExportImportFlags(os.Args[4:])
+			if err != nil {
+				return fmt.Errorf("invalid flags for \"kp space import\": %v", err)
+			}
+
+			if err := ImportSpace(client, path, spaceID, conf.APIKey, dryRun); err != nil {
+				return fmt.Errorf("failed to import space: %v", err)
+			}
 		default:
 			return fmt.Errorf("unknown command %q for \"kp space\"\n\n%s", os.Args[2], spaceViewUsage)
 		}
@@ -261,11 +338,15 @@ func Run() error {
 			}
 			spaceID := os.Args[3]
 			boxID := os.Args[4]
-			cards, err := CardsInBox(spaceID, boxID, conf.APIKey)
+			timeSlice, _, _, err := parseRoundRobinFlags(os.Args[5:])
+			if err != nil {
+				return fmt.Errorf("invalid flags for \"kp roundrobin set\": %v", err)
+			}
+			cards, err := CardsInBoxCached(client, conf, spaceID, boxID, conf.APIKey, offline, refresh)
 			if err != nil {
 				return fmt.Errorf("failed to retrieve box: %v", err)
 			}
-			if err := setRoundRobin(conf, cards, 0*time.Minute); err != nil {
+			if err := setRoundRobin(conf, cards, timeSlice); err != nil {
 				return fmt.Errorf("failed to set round-robin box: %v", err)
 			}
 		case `next`:
@@ -274,6 +355,14 @@ func Run() error {
 				return fmt.Errorf("failed to retrieve next topic: %v", err)
 			}
 			fmt.Println(topic.Name)
+		case `run`:
+			timeSlice, rounds, pause, err := parseRoundRobinFlags(os.Args[3:])
+			if err != nil {
+				return fmt.Errorf("invalid flags for \"kp roundrobin run\": %v", err)
+			}
+			if err := RunRoundRobin(conf, timeSlice, rounds, pause); err != nil {
+				return fmt.Errorf("failed to run round-robin: %v", err)
+			}
 		case `clear`:
 			if err := conf.Schedule.clear(conf); err != nil {
 				return fmt.Errorf("failed to clear scheduler: %v", err)
@@ -281,6 +370,35 @@ func Run() error {
 		default:
 			return fmt.Errorf("Unknown command: %q.\n%s\n", os.Args[3], usage)
 		}
+	case `tui`:
+		if err := RunTUI(client, conf); err != nil {
+			return fmt.Errorf("failed to run tui: %v", err)
+		}
+	case `cache`:
+		if len(args) < 3 {
+			fmt.Println(cacheUsage)
+			return nil
+		}
+		switch strings.ToLower(os.Args[2]) {
+		case `clear`:
+			if err := clearCache(conf); err != nil {
+				return fmt.Errorf("failed to clear cache: %v", err)
+			}
+		case `status`:
+			lines, err := cacheStatus(conf)
+			if err != nil {
+				return fmt.Errorf("failed to get cache status: %v", err)
+			}
+			if len(lines) == 0 {
+				fmt.Println("cache is empty")
+				return nil
+			}
+			for _, l := range lines {
+				fmt.Println(l)
+			}
+		default:
+			return fmt.Errorf("unknown command %q for \"kp cache\"\n\n%s", os.Args[2], cacheUsage)
+		}
 	case `help`:
 		fmt.Println(usage)
 	default:
@@ -290,7 +408,10 @@ func Run() error {
 	return nil
 }
 
-// LoadConfig loads in the config file.
+// LoadConfig loads the layered config: the user-level
+// ~/.config/kinopio/kinopio.yaml, overlaid with a per-project
+// .kinopio.yaml discovered by walking up from $PWD (if any), overlaid
+// with KINOPIO_* environment variables.
 func LoadConfig() (*Config, error) {
 	dir, err := os.UserConfigDir()
 	if err != nil {
@@ -317,12 +438,28 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("error unmarshalling config file: %v", err)
 	}
 
+	if projectPath, ok := findProjectConfig(); ok {
+		data, err := os.ReadFile(projectPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading project config file %q: %v", projectPath, err)
+		}
+		if err := yaml.Unmarshal(data, &conf); err != nil {
+			return nil, fmt.Errorf("error unmarshalling project config file %q: %v", projectPath, err)
+		}
+	}
+
+	conf.applyEnv()
+
 	if conf.APIKey == "" {
-		return nil, fmt.Errorf("api_key must be set in config file. Use `kp config` to open the config file with your $EDITOR")
+		return nil, fmt.Errorf("api_key must be set in config file or KINOPIO_API_KEY. Use `kp config` to open the config file with your $EDITOR")
 	}
 
 	if conf.InboxSpaceID == "" {
-		return nil, fmt.Errorf("inbox_space_id must be set in config file. Use `kp config` to open the config file with your $EDITOR")
+		return nil, fmt.Errorf("inbox_space_id must be set in config file or KINOPIO_INBOX_SPACE_ID. Use `kp config` to open the config file with your $EDITOR")
+	}
+
+	if conf.APIURL == "" {
+		conf.APIURL = defaultAPIURL
 	}
 
 	conf.DirPath = dirPath
@@ -331,6 +468,43 @@ func LoadConfig() (*Config, error) {
 	return &conf, nil
 }
 
+// findProjectConfig walks up from the current directory looking for a
+// .kinopio.yaml overlay, returning its path if one is found.
+func findProjectConfig() (string, bool) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		path := filepath.Join(dir, `.kinopio.yaml`)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// applyEnv overlays KINOPIO_API_KEY, KINOPIO_INBOX_SPACE_ID, and
+// KINOPIO_API_URL on top of c, so CI and password managers can supply
+// secrets without writing them to disk.
+func (c *Config) applyEnv() {
+	if v := os.Getenv(`KINOPIO_API_KEY`); v != "" {
+		c.APIKey = v
+	}
+	if v := os.Getenv(`KINOPIO_INBOX_SPACE_ID`); v != "" {
+		c.InboxSpaceID = v
+	}
+	if v := os.Getenv(`KINOPIO_API_URL`); v != "" {
+		c.APIURL = v
+	}
+}
+
 // createConfig creates a config directory if one doesn't exist
 // and creates a config file. This function will overwrite an
 // existing config file
@@ -357,7 +531,7 @@ func createConfig(dirPath, filePath string) error {
 }
 
 // AddCardToInbox creates a new card in user's inbox space.
-func AddCardToInbox(c Card, key string) error {
+func AddCardToInbox(client *Client, c Card, key string) error {
 	if c.Name == "" {
 		return fmt.Errorf("card content cannot be empty")
 	}
@@ -367,7 +541,7 @@ func AddCardToInbox(c Card, key string) error {
 		return fmt.Errorf("error marshalling card data: %v", err)
 	}
 
-	inboxURL := fmt.Sprintf("%s/card/to-inbox", apiURL)
+	inboxURL := fmt.Sprintf("%s/card/to-inbox", client.baseURL)
 
 	req, err := http.NewRequest("POST", inboxURL, bytes.NewBuffer(cardData))
 	if err != nil {
@@ -377,10 +551,9 @@ func AddCardToInbox(c Card, key string) error {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", key)
 
-	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("error making request: %v", err)
+		return fmt.Errorf("failed to create card: %v", err)
 	}
 	defer resp.Body.Close()
 
@@ -391,9 +564,154 @@ func AddCardToInbox(c Card, key string) error {
 	return nil
 }
 
+// CreateCard creates a new card in the space named by c.SpaceID, at
+// whatever box/position it carries. Unlike AddCardToInbox it isn't
+// restricted to the inbox space, which is what `kp space import` needs.
+func CreateCard(client *Client, c Card, key string) (Card, error) {
+	if c.Name == "" {
+		return Card{}, fmt.Errorf("card content cannot be empty")
+	}
+
+	cardData, err := json.Marshal(c)
+	if err != nil {
+		return Card{}, fmt.Errorf("error marshalling card data: %v", err)
+	}
+
+	cardURL := fmt.Sprintf("%s/space/%s/card", client.baseURL, c.SpaceID)
+
+	req, err := http.NewRequest("POST", cardURL, bytes.NewBuffer(cardData))
+	if err != nil {
+		return Card{}, fmt.Errorf("error creating request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", key)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Card{}, fmt.Errorf("failed to create card: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Card{}, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	var created Card
+	if err := json.Unmarshal(body, &created); err != nil {
+		return Card{}, fmt.Errorf("failed to unmarshall JSON: %v", err)
+	}
+
+	return created, nil
+}
+
+// UpdateCard updates an existing card in the space named by c.SpaceID.
+func UpdateCard(client *Client, c Card, key string) (Card, error) {
+	if c.ID == "" {
+		return Card{}, fmt.Errorf("card id cannot be empty")
+	}
+	if c.Name == "" {
+		return Card{}, fmt.Errorf("card content cannot be empty")
+	}
+
+	cardData, err := json.Marshal(c)
+	if err != nil {
+		return Card{}, fmt.Errorf("error marshalling card data: %v", err)
+	}
+
+	cardURL := fmt.Sprintf("%s/space/%s/card/%s", client.baseURL, c.SpaceID, c.ID)
+
+	req, err := http.NewRequest("PUT", cardURL, bytes.NewBuffer(cardData))
+	if err != nil {
+		return Card{}, fmt.Errorf("error creating request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", key)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Card{}, fmt.Errorf("failed to update card: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Card{}, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	var updated Card
+	if err := json.Unmarshal(body, &updated); err != nil {
+		return Card{}, fmt.Errorf("failed to unmarshall JSON: %v", err)
+	}
+
+	return updated, nil
+}
+
+// DeleteCard removes a card from the space named by spaceID.
+func DeleteCard(client *Client, spaceID, cardID, key string) error {
+	cardURL := fmt.Sprintf("%s/space/%s/card/%s", client.baseURL, spaceID, cardID)
+
+	req, err := http.NewRequest("DELETE", cardURL, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+
+	req.Header.Set("Authorization", key)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete card: %v", err)
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// CreateBox creates a new box in the given space.
+func CreateBox(client *Client, spaceID string, b Box, key string) (Box, error) {
+	if b.Name == "" {
+		return Box{}, fmt.Errorf("box name cannot be empty")
+	}
+
+	boxData, err := json.Marshal(b)
+	if err != nil {
+		return Box{}, fmt.Errorf("error marshalling box data: %v", err)
+	}
+
+	boxURL := fmt.Sprintf("%s/space/%s/box", client.baseURL, spaceID)
+
+	req, err := http.NewRequest("POST", boxURL, bytes.NewBuffer(boxData))
+	if err != nil {
+		return Box{}, fmt.Errorf("error creating request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", key)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Box{}, fmt.Errorf("failed to create box: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Box{}, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	var created Box
+	if err := json.Unmarshal(body, &created); err != nil {
+		return Box{}, fmt.Errorf("failed to unmarshall JSON: %v", err)
+	}
+
+	return created, nil
+}
+
 // GetSpaces returns user spaces
-func GetSpaces(key string) ([]Space, error) {
-	spaceURL := fmt.Sprintf("%s/user/spaces", apiURL)
+func GetSpaces(client *Client, key string) ([]Space, error) {
+	spaceURL := fmt.Sprintf("%s/user/spaces", client.baseURL)
 
 	req, err := http.NewRequest("GET", spaceURL, nil)
 	if err != nil {
@@ -403,10 +721,9 @@ func GetSpaces(key string) ([]Space, error) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", key)
 
-	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return []Space{}, fmt.Errorf("error making request: %v", err)
+		return []Space{}, fmt.Errorf("failed to retrieve spaces: %v", err)
 	}
 	defer resp.Body.Close()
 
@@ -427,8 +744,8 @@ func GetSpaces(key string) ([]Space, error) {
 }
 
 // GetSpace returns a user space
-func GetSpace(id, key string) (Space, error) {
-	spaceURL := fmt.Sprintf("%s/space/%s", apiURL, id)
+func GetSpace(client *Client, id, key string) (Space, error) {
+	spaceURL := fmt.Sprintf("%s/space/%s", client.baseURL, id)
 
 	req, err := http.NewRequest("GET", spaceURL, nil)
 	if err != nil {
@@ -438,10 +755,9 @@ func GetSpace(id, key string) (Space, error) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", key)
 
-	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return Space{}, fmt.Errorf("error making request: %v", err)
+		return Space{}, fmt.Errorf("failed to retrieve space: %v", err)
 	}
 	defer resp.Body.Close()
 
@@ -462,8 +778,8 @@ func GetSpace(id, key string) (Space, error) {
 }
 
 // getBox returns a user box
-func getBox(id, key string) (Box, error) {
-	boxURL := fmt.Sprintf("%s/box/%s", apiURL, id)
+func getBox(client *Client, id, key string) (Box, error) {
+	boxURL := fmt.Sprintf("%s/box/%s", client.baseURL, id)
 
 	req, err := http.NewRequest("GET", boxURL, nil)
 	if err != nil {
@@ -473,10 +789,9 @@ func getBox(id, key string) (Box, error) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", key)
 
-	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return Box{}, fmt.Errorf("error making request: %v", err)
+		return Box{}, fmt.Errorf("failed to retrieve box: %v", err)
 	}
 	defer resp.Body.Close()
 
@@ -497,14 +812,14 @@ func getBox(id, key string) (Box, error) {
 }
 
 // CardsInBox returns all cards that reside within a given box
-func CardsInBox(spaceID, boxID, key string) ([]Card, error) {
+func CardsInBox(client *Client, spaceID, boxID, key string) ([]Card, error) {
 	// Get space
-	space, err := GetSpace(spaceID, key)
+	space, err := GetSpace(client, spaceID, key)
 	if err != nil {
 		return []Card{}, fmt.Errorf("failed to retrieve space %q: %v", spaceID, err)
 	}
 	// Get box
-	box, err := getBox(boxID, key)
+	box, err := getBox(client, boxID, key)
 	if err != nil {
 		return []Card{}, fmt.Errorf("failed to retrieve box %q: %v", boxID, err)
 	}
@@ -560,20 +875,19 @@ func (s *Scheduler) next(conf *Config) (Topic, error) {
 	}
 
 	current := s.Topics[s.Current]
-	elapsed := time.Since(current.Start)
-	if elapsed < s.TimeSlice {
+	if current.elapsed() < s.TimeSlice {
 		return current, nil
 	}
 
 	s.Current = (s.Current + 1) % len(s.Topics)
+	s.Topics[s.Current].Start = time.Now()
+	s.Topics[s.Current].Duration = 0
 	conf.Schedule = *s
 	if err := conf.SaveConfig(); err != nil {
 		return Topic{}, fmt.Errorf("failed to save schedule topics: %v", err)
 	}
 
-	next := s.Topics[s.Current]
-	next.Start = time.Now()
-	return next, nil
+	return s.Topics[s.Current], nil
 }
 
 // clear clears the schedule
@@ -606,9 +920,24 @@ func (c Config) OpenConfig(editor string) error {
 	return nil
 }
 
-// SaveConfig writes the given config to the file at config file path.
+// SaveConfig writes the scheduler state back to the user-level config
+// file. It re-reads that file first and only updates the Schedule
+// field, so api_key/inbox_space_id/api_url values merged in from
+// KINOPIO_* env vars or a project .kinopio.yaml overlay never leak
+// into the on-disk user config.
 func (c Config) SaveConfig() error {
-	data, err := yaml.Marshal(c)
+	onDisk, err := os.ReadFile(c.FilePath)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %v", err)
+	}
+
+	var userConf Config
+	if err := yaml.Unmarshal(onDisk, &userConf); err != nil {
+		return fmt.Errorf("error unmarshalling config file: %v", err)
+	}
+	userConf.Schedule = c.Schedule
+
+	data, err := yaml.Marshal(userConf)
 	if err != nil {
 		return err
 	}
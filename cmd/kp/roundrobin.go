@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gen2brain/beeep"
+)
+
+// RunRoundRobin blocks in the foreground, advancing conf.Schedule from
+// topic to topic as each one's time slice elapses. It prints a progress
+// bar for the topic currently running and fires a desktop notification
+// whenever it advances. SIGINT/SIGTERM persists the current topic index
+// to conf.Schedule before exiting, so the session can be resumed later
+// with another `run` or with `kp roundrobin next`. --pause freezes the
+// current topic's clock instead: it banks the elapsed time into its
+// Duration and clears Start, so a later `run` resumes the same slice
+// rather than finding it already expired from real time passing while
+// the process wasn't running.
+func RunRoundRobin(conf *Config, timeSlice time.Duration, rounds int, pause bool) error {
+	if len(conf.Schedule.Topics) == 0 {
+		return fmt.Errorf("no topics found, use `kp roundrobin set` first")
+	}
+
+	if timeSlice > 0 {
+		conf.Schedule.TimeSlice = timeSlice
+	}
+	if conf.Schedule.TimeSlice <= 0 {
+		return fmt.Errorf("time slice must be set, use --time-slice or `kp roundrobin set`")
+	}
+
+	current := &conf.Schedule.Topics[conf.Schedule.Current]
+	if current.Start.IsZero() {
+		current.Start = time.Now()
+	}
+
+	if pause {
+		current.Duration = current.elapsed()
+		current.Start = time.Time{}
+		remaining := conf.Schedule.TimeSlice - current.Duration
+		if remaining < 0 {
+			remaining = 0
+		}
+		fmt.Printf("%s: %s remaining\n", current.Name, remaining.Round(time.Second))
+		return conf.SaveConfig()
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+	completed := 0
+	for rounds <= 0 || completed < rounds {
+		select {
+		case <-sigCh:
+			fmt.Println()
+			return conf.SaveConfig()
+		default:
+		}
+
+		current := conf.Schedule.Topics[conf.Schedule.Current]
+		elapsed := current.elapsed()
+		if elapsed >= conf.Schedule.TimeSlice {
+			next, err := conf.Schedule.next(conf)
+			if err != nil {
+				return fmt.Errorf("failed to advance scheduler: %v", err)
+			}
+			fmt.Println()
+			notifyNextTopic(next)
+			if conf.Schedule.Current == 0 {
+				completed++
+			}
+			continue
+		}
+
+		printProgress(current, elapsed, conf.Schedule.TimeSlice)
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	fmt.Println()
+	return nil
+}
+
+// printProgress renders a single-line progress bar for the topic
+// currently running, e.g. "writing docs [##########----------] 1m15s remaining".
+func printProgress(t Topic, elapsed, slice time.Duration) {
+	const width = 20
+
+	frac := float64(elapsed) / float64(slice)
+	if frac > 1 {
+		frac = 1
+	}
+	filled := int(frac * width)
+	bar := strings.Repeat("#", filled) + strings.Repeat("-", width-filled)
+
+	remaining := slice - elapsed
+	if remaining < 0 {
+		remaining = 0
+	}
+	fmt.Printf("\r%s [%s] %s remaining", t.Name, bar, remaining.Round(time.Second))
+}
+
+// notifyNextTopic fires a desktop notification announcing the new
+// current topic.
+func notifyNextTopic(t Topic) {
+	if err := beeep.Notify("kp round-robin", fmt.Sprintf("Now on: %s", t.Name), ""); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to send notification: %v\n", err)
+	}
+}
+
+// parseRoundRobinFlags parses --time-slice, --rounds, and --pause out
+// of args, the tail of os.Args following a `kp roundrobin` subcommand.
+func parseRoundRobinFlags(args []string) (timeSlice time.Duration, rounds int, pause bool, err error) {
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case `--time-slice`:
+			i++
+			if i >= len(args) {
+				return 0, 0, false, fmt.Errorf("--time-slice requires a value")
+			}
+			timeSlice, err = time.ParseDuration(args[i])
+			if err != nil {
+				return 0, 0, false, fmt.Errorf("invalid --time-slice: %v", err)
+			}
+		case `--rounds`:
+			i++
+			if i >= len(args) {
+				return 0, 0, false, fmt.Errorf("--rounds requires a value")
+			}
+			rounds, err = strconv.Atoi(args[i])
+			if err != nil {
+				return 0, 0, false, fmt.Errorf("invalid --rounds: %v", err)
+			}
+		case `--pause`:
+			pause = true
+		default:
+			return 0, 0, false, fmt.Errorf("unknown flag %q", args[i])
+		}
+	}
+	return timeSlice, rounds, pause, nil
+}
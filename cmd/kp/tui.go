@@ -0,0 +1,311 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// tuiApp bundles the widgets that make up the `kp tui` screen along with
+// the config and API key needed to keep talking to Kinopio while the
+// user navigates.
+type tuiApp struct {
+	conf   *Config
+	client *Client
+	key    string
+
+	app       *tview.Application
+	pages     *tview.Pages
+	spaceList *tview.List
+	boxList   *tview.List
+	cardList  *tview.List
+	status    *tview.TextView
+
+	space Space
+	box   *Box   // box currently focused in the box pane, nil if browsing the whole space
+	cards []Card // cards backing the current cardList, in display order
+}
+
+// RunTUI launches the full-screen terminal UI for browsing spaces,
+// boxes, and cards.
+func RunTUI(client *Client, conf *Config) error {
+	t := &tuiApp{
+		conf:   conf,
+		client: client,
+		key:    conf.APIKey,
+		app:    tview.NewApplication(),
+	}
+
+	spaces, err := GetSpaces(t.client, t.key)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve user spaces: %v", err)
+	}
+
+	t.status = tview.NewTextView().SetDynamicColors(true)
+	t.setStatus()
+
+	t.spaceList = tview.NewList().ShowSecondaryText(false)
+	t.spaceList.SetBorder(true).SetTitle(" Spaces ")
+	for _, s := range spaces {
+		space := s
+		t.spaceList.AddItem(space.Name, "", 0, func() {
+			t.openSpace(space)
+		})
+	}
+
+	t.boxList = tview.NewList().ShowSecondaryText(false)
+	t.boxList.SetBorder(true).SetTitle(" Boxes ")
+
+	t.cardList = tview.NewList().ShowSecondaryText(false)
+	t.cardList.SetBorder(true).SetTitle(" Cards ")
+
+	main := tview.NewFlex().
+		AddItem(t.spaceList, 0, 1, true).
+		AddItem(t.boxList, 0, 1, false).
+		AddItem(t.cardList, 0, 2, false)
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(main, 0, 1, true).
+		AddItem(t.status, 1, 0, false)
+
+	t.pages = tview.NewPages().AddPage("main", layout, true, true)
+
+	t.app.SetInputCapture(t.handleKey)
+
+	if err := t.app.SetRoot(t.pages, true).SetFocus(t.spaceList).Run(); err != nil {
+		return fmt.Errorf("failed to run tui: %v", err)
+	}
+	return nil
+}
+
+// openSpace loads a space's boxes and cards into the box and card panes.
+func (t *tuiApp) openSpace(space Space) {
+	full, err := GetSpace(t.client, space.ID, t.key)
+	if err != nil {
+		t.setError(fmt.Errorf("failed to retrieve space: %v", err))
+		return
+	}
+	t.space = full
+	t.box = nil
+
+	t.boxList.Clear()
+	for _, b := range full.Boxes {
+		box := b
+		t.boxList.AddItem(box.Name, "", 0, func() {
+			t.openBox(box)
+		})
+	}
+
+	t.setCards(full.Cards)
+
+	t.app.SetFocus(t.boxList)
+}
+
+// openBox filters the card pane down to the cards that live in box.
+func (t *tuiApp) openBox(box Box) {
+	cards, err := CardsInBox(t.client, t.space.ID, box.ID, t.key)
+	if err != nil {
+		t.setError(fmt.Errorf("failed to retrieve box: %v", err))
+		return
+	}
+	t.box = &box
+
+	t.setCards(cards)
+
+	t.app.SetFocus(t.cardList)
+}
+
+// setCards replaces the card pane's contents with cards, keeping
+// t.cards in sync so keybindings can map a selected list row back to
+// the full Card struct.
+func (t *tuiApp) setCards(cards []Card) {
+	t.cards = cards
+	t.cardList.Clear()
+	for _, c := range cards {
+		t.cardList.AddItem(c.Name, c.ID, 0, nil)
+	}
+}
+
+// selectedCard returns the card currently highlighted in the card
+// pane, if any.
+func (t *tuiApp) selectedCard() (Card, bool) {
+	idx := t.cardList.GetCurrentItem()
+	if idx < 0 || idx >= len(t.cards) {
+		return Card{}, false
+	}
+	return t.cards[idx], true
+}
+
+// handleKey wires up the keybindings shown in the status bar: `a` adds
+// a card to the space/box currently being browsed, `e` edits and `d`
+// deletes the card highlighted in the card pane, `r` starts a
+// round-robin session from the box currently focused in the box pane,
+// and `q`/Ctrl-C quits.
+func (t *tuiApp) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	// Leave keys to an open form or modal so card names can be typed.
+	if front, _ := t.pages.GetFrontPage(); front != "main" {
+		return event
+	}
+	switch event.Rune() {
+	case 'q':
+		t.app.Stop()
+		return nil
+	case 'a':
+		t.promptAddCard()
+		return nil
+	case 'e':
+		t.promptEditCard()
+		return nil
+	case 'd':
+		t.promptDeleteCard()
+		return nil
+	case 'r':
+		t.startRoundRobin()
+		return nil
+	}
+	return event
+}
+
+// promptAddCard shows a single-line form for adding a new card to the
+// space currently being browsed. If a box is focused in the box pane,
+// the card is placed inside that box's bounds so it shows up there
+// immediately; otherwise it's dropped at the space's origin.
+func (t *tuiApp) promptAddCard() {
+	if t.space.ID == "" {
+		return
+	}
+	form := tview.NewForm()
+	form.AddInputField("Name", "", 40, nil, nil)
+	form.AddButton("Add", func() {
+		name := form.GetFormItem(0).(*tview.InputField).GetText()
+		c := Card{Name: name, SpaceID: t.space.ID}
+		if t.box != nil {
+			c.X = t.box.X + 10
+			c.Y = t.box.Y + 10
+		}
+		created, err := CreateCard(t.client, c, t.key)
+		if err != nil {
+			t.setError(fmt.Errorf("failed to add card: %v", err))
+		} else {
+			t.setCards(append(t.cards, created))
+			t.setStatus()
+		}
+		t.pages.RemovePage("add")
+		t.app.SetFocus(t.cardList)
+	})
+	form.AddButton("Cancel", func() {
+		t.pages.RemovePage("add")
+		t.app.SetFocus(t.cardList)
+	})
+	form.SetBorder(true).SetTitle(" Add card ")
+	t.pages.AddPage("add", centered(form, 40, 7), true, true)
+}
+
+// promptEditCard shows a single-line form, pre-filled with the
+// highlighted card's name, for renaming it in place.
+func (t *tuiApp) promptEditCard() {
+	card, ok := t.selectedCard()
+	if !ok {
+		return
+	}
+	form := tview.NewForm()
+	form.AddInputField("Name", card.Name, 40, nil, nil)
+	form.AddButton("Save", func() {
+		card.Name = form.GetFormItem(0).(*tview.InputField).GetText()
+		updated, err := UpdateCard(t.client, card, t.key)
+		if err != nil {
+			t.setError(fmt.Errorf("failed to edit card: %v", err))
+		} else {
+			idx := t.cardList.GetCurrentItem()
+			t.cards[idx] = updated
+			t.cardList.SetItemText(idx, updated.Name, updated.ID)
+			t.setStatus()
+		}
+		t.pages.RemovePage("edit")
+		t.app.SetFocus(t.cardList)
+	})
+	form.AddButton("Cancel", func() {
+		t.pages.RemovePage("edit")
+		t.app.SetFocus(t.cardList)
+	})
+	form.SetBorder(true).SetTitle(" Edit card ")
+	t.pages.AddPage("edit", centered(form, 40, 7), true, true)
+}
+
+// promptDeleteCard asks for confirmation before deleting the card
+// highlighted in the card pane.
+func (t *tuiApp) promptDeleteCard() {
+	card, ok := t.selectedCard()
+	if !ok {
+		return
+	}
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Delete %q?", card.Name)).
+		AddButtons([]string{"Delete", "Cancel"}).
+		SetDoneFunc(func(_ int, label string) {
+			if label == "Delete" {
+				if err := DeleteCard(t.client, t.space.ID, card.ID, t.key); err != nil {
+					t.setError(fmt.Errorf("failed to delete card: %v", err))
+				} else {
+					idx := t.cardList.GetCurrentItem()
+					t.setCards(append(t.cards[:idx], t.cards[idx+1:]...))
+					t.setStatus()
+				}
+			}
+			t.pages.RemovePage("delete")
+			t.app.SetFocus(t.cardList)
+		})
+	t.pages.AddPage("delete", modal, true, true)
+}
+
+// startRoundRobin sets the scheduler's topics to the cards in the
+// currently selected box, the same as `kp roundrobin set`.
+func (t *tuiApp) startRoundRobin() {
+	idx := t.boxList.GetCurrentItem()
+	if idx < 0 || idx >= len(t.space.Boxes) {
+		return
+	}
+	box := t.space.Boxes[idx]
+
+	cards, err := CardsInBox(t.client, t.space.ID, box.ID, t.key)
+	if err != nil {
+		t.setError(fmt.Errorf("failed to retrieve box: %v", err))
+		return
+	}
+	if err := setRoundRobin(t.conf, cards, t.conf.Schedule.TimeSlice); err != nil {
+		t.setError(fmt.Errorf("failed to set round-robin box: %v", err))
+		return
+	}
+	t.setStatus()
+}
+
+// setStatus refreshes the status bar with the currently active
+// round-robin topic, if any.
+func (t *tuiApp) setStatus() {
+	const keys = "[a]dd  [e]dit  [d]elete  [r]ound-robin from box  [q]uit"
+	topics := t.conf.Schedule.Topics
+	if len(topics) == 0 {
+		t.status.SetText(keys + "  |  round-robin: none")
+		return
+	}
+	current := topics[t.conf.Schedule.Current]
+	t.status.SetText(fmt.Sprintf("%s  |  round-robin: %s", keys, current.Name))
+}
+
+// setError reports err on the status bar so the TUI never has to exit
+// on a failed request.
+func (t *tuiApp) setError(err error) {
+	t.status.SetText(fmt.Sprintf("[red]%v", err))
+}
+
+// centered wraps p in a flex box that centers it within a w x h region.
+func centered(p tview.Primitive, w, h int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, h, 0, true).
+			AddItem(nil, 0, 1, false), w, 0, true).
+		AddItem(nil, 0, 1, false)
+}